@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/oauth2"
+)
+
+const tokenFile = "token.json"
+
+// getClient returns an HTTP client authorized against the Calendar API,
+// running the interactive loopback OAuth flow if no cached token.json
+// exists yet. The returned client automatically refreshes its access
+// token and re-persists token.json whenever the refresh token rotates.
+func getClient(ctx context.Context, config *oauth2.Config) (*http.Client, error) {
+	tok, err := tokenFromFile(tokenFile)
+	if err != nil {
+		tok, err = getTokenFromWeb(config)
+		if err != nil {
+			return nil, err
+		}
+		if err := saveToken(tokenFile, tok); err != nil {
+			return nil, err
+		}
+	}
+
+	ts := &persistingTokenSource{
+		wrapped:      config.TokenSource(ctx, tok),
+		refreshToken: tok.RefreshToken,
+	}
+	return oauth2.NewClient(ctx, ts), nil
+}
+
+// persistingTokenSource wraps an oauth2.TokenSource and re-persists
+// token.json whenever the refresh token rotates, which Google does
+// periodically.
+type persistingTokenSource struct {
+	wrapped      oauth2.TokenSource
+	refreshToken string
+}
+
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.wrapped.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken != "" && tok.RefreshToken != s.refreshToken {
+		s.refreshToken = tok.RefreshToken
+		if err := saveToken(tokenFile, tok); err != nil {
+			return nil, err
+		}
+	}
+	return tok, nil
+}
+
+// getTokenFromWeb runs a PKCE-protected OAuth loopback flow: it starts a
+// local HTTP server, opens the authorization URL in the user's browser,
+// and exchanges the code it receives on the callback. This replaces the
+// old copy-paste-the-code flow, which Google has been phasing out.
+func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
+	verifier, err := pkceVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate PKCE verifier: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to start loopback listener: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	config.RedirectURL = fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		if authErr := r.URL.Query().Get("error"); authErr != "" {
+			errCh <- fmt.Errorf("authorization denied: %s", authErr)
+			fmt.Fprintln(w, "Authorization denied, you can close this tab.")
+			return
+		}
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			errCh <- fmt.Errorf("callback missing authorization code")
+			fmt.Fprintln(w, "Missing authorization code, you can close this tab.")
+			return
+		}
+		fmt.Fprintln(w, "Authorization complete, you can close this tab.")
+		codeCh <- code
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("Opening browser for authorization. If it doesn't open, visit:\n%v\n", authURL)
+	openBrowser(authURL)
+
+	select {
+	case code := <-codeCh:
+		tok, err := config.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve token from web: %v", err)
+		}
+		return tok, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+// pkceVerifier generates a cryptographically random PKCE code verifier.
+func pkceVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	_ = cmd.Start()
+}
+
+func tokenFromFile(file string) (*oauth2.Token, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tok := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(tok)
+	return tok, err
+}
+
+func saveToken(path string, token *oauth2.Token) error {
+	fmt.Printf("Saving credential file to: %s\n", path)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to cache oauth token: %v", err)
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(token)
+}
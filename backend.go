@@ -0,0 +1,19 @@
+package main
+
+// targetCalendar returns the calendar a template should be scheduled on,
+// defaulting to the user's primary calendar when no CalendarName was
+// resolved (or none was configured).
+func targetCalendar(template EventTemplate) string {
+	if template.calendarID == "" {
+		return "primary"
+	}
+	return template.calendarID
+}
+
+// CalendarBackend creates recurring calendar events from an EventTemplate.
+// Each backend is responsible for translating the template into whatever
+// wire format its calendar service expects and returning an identifier
+// that can be stored in event_ids.json.
+type CalendarBackend interface {
+	CreateRecurringEvent(template EventTemplate, timeZone string) (string, error)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// CalDAVBackend implements CalendarBackend against any CalDAV server
+// (Nextcloud, Radicale, Fastmail, ...). It PUTs one VEVENT per template
+// into a configured calendar collection.
+type CalDAVBackend struct {
+	client       *caldav.Client
+	calendarPath string
+}
+
+// CalDAVConfig holds the connection details read from the environment.
+type CalDAVConfig struct {
+	URL          string // e.g. https://nextcloud.example.com/remote.php/dav
+	Username     string
+	AppPassword  string
+	CalendarPath string // path of the target calendar collection
+}
+
+// CalDAVConfigFromEnv reads CALDAV_URL, CALDAV_USER, CALDAV_PASSWORD and
+// CALDAV_CALENDAR_PATH from the environment.
+func CalDAVConfigFromEnv() (CalDAVConfig, error) {
+	cfg := CalDAVConfig{
+		URL:          os.Getenv("CALDAV_URL"),
+		Username:     os.Getenv("CALDAV_USER"),
+		AppPassword:  os.Getenv("CALDAV_PASSWORD"),
+		CalendarPath: os.Getenv("CALDAV_CALENDAR_PATH"),
+	}
+	if cfg.URL == "" || cfg.Username == "" || cfg.AppPassword == "" || cfg.CalendarPath == "" {
+		return cfg, fmt.Errorf("CALDAV_URL, CALDAV_USER, CALDAV_PASSWORD and CALDAV_CALENDAR_PATH must all be set")
+	}
+	return cfg, nil
+}
+
+// NewCalDAVBackend builds a CalDAV backend from the given config.
+func NewCalDAVBackend(cfg CalDAVConfig) (*CalDAVBackend, error) {
+	httpClient := &http.Client{
+		Transport: &basicAuthTransport{
+			username: cfg.Username,
+			password: cfg.AppPassword,
+			base:     http.DefaultTransport,
+		},
+	}
+
+	client, err := caldav.NewClient(httpClient, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create CalDAV client: %v", err)
+	}
+
+	return &CalDAVBackend{client: client, calendarPath: cfg.CalendarPath}, nil
+}
+
+type basicAuthTransport struct {
+	username string
+	password string
+	base     http.RoundTripper
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.SetBasicAuth(t.username, t.password)
+	return t.base.RoundTrip(req)
+}
+
+// CreateRecurringEvent implements CalendarBackend by translating the
+// template into a single VEVENT carrying the RRULE (and EXDATEs, if any)
+// built from its Recurrence, and PUTting it under the configured calendar
+// collection.
+func (c *CalDAVBackend) CreateRecurringEvent(template EventTemplate, timeZone string) (string, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return "", fmt.Errorf("error loading time zone: %v", err)
+	}
+
+	finalStartTime, finalEndTime, err := firstOccurrence(template)
+	if err != nil {
+		return "", err
+	}
+	finalStartTime = finalStartTime.In(location)
+	finalEndTime = finalEndTime.In(location)
+
+	rruleLine, err := buildRRule(template.recurrence, finalStartTime)
+	if err != nil {
+		return "", err
+	}
+
+	uid := fmt.Sprintf("%d-%s@health-calendar", time.Now().UnixNano(), sanitizeUID(template.summary))
+
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, uid)
+	event.Props.SetText(ical.PropSummary, template.summary)
+	event.Props.SetText(ical.PropDescription, template.description)
+	event.Props.SetDateTime(ical.PropDateTimeStart, finalStartTime)
+	event.Props.SetDateTime(ical.PropDateTimeEnd, finalEndTime)
+	event.Props.SetText(ical.PropRecurrenceRule, strings.TrimPrefix(rruleLine, "RRULE:"))
+	if exDateLine := buildExDates(template.recurrence.ExDates); exDateLine != "" {
+		event.Props.SetText(ical.PropExceptionDates, strings.TrimPrefix(exDateLine, "EXDATE:"))
+	}
+
+	if template.reminderMin > 0 {
+		alarm := ical.NewComponent(ical.CompAlarm)
+		alarm.Props.SetText(ical.PropAction, "DISPLAY")
+		alarm.Props.SetText(ical.PropDescription, template.summary)
+		alarm.Props.SetText(ical.PropTrigger, fmt.Sprintf("-PT%dM", template.reminderMin))
+		event.Children = append(event.Children, alarm)
+	}
+
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//health-calendar//EN")
+	cal.Children = append(cal.Children, event.Component)
+
+	objectPath := c.calendarPath + "/" + uid + ".ics"
+	if _, err := c.client.PutCalendarObject(context.Background(), objectPath, cal); err != nil {
+		return "", fmt.Errorf("unable to PUT calendar object: %v", err)
+	}
+
+	return uid, nil
+}
+
+func sanitizeUID(summary string) string {
+	out := make([]rune, 0, len(summary))
+	for _, r := range summary {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
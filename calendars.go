@@ -0,0 +1,67 @@
+package main
+
+import "fmt"
+
+// CalendarResolver is implemented by backends that can resolve
+// human-readable calendar names (as used in config.yaml) to the IDs the
+// backend's API actually expects.
+type CalendarResolver interface {
+	ResolveCalendarIDs(names []string) (map[string]string, error)
+}
+
+// ResolveCalendarIDs implements CalendarResolver for GoogleBackend. It
+// calls CalendarList.List once and caches the name -> ID mapping for the
+// lifetime of the backend.
+func (g *GoogleBackend) ResolveCalendarIDs(names []string) (map[string]string, error) {
+	if g.calendarsByName == nil {
+		list, err := g.srv.CalendarList.List().Do()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list calendars: %v", err)
+		}
+		g.calendarsByName = make(map[string]string, len(list.Items))
+		for _, item := range list.Items {
+			g.calendarsByName[item.Summary] = item.Id
+		}
+	}
+
+	resolved := make(map[string]string, len(names))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		id, ok := g.calendarsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("no calendar named %q found in the user's calendar list", name)
+		}
+		resolved[name] = id
+	}
+	return resolved, nil
+}
+
+// applyCalendarIDs resolves each template's CalendarName (if any) to a
+// calendarID using resolver, leaving templates with no CalendarName
+// targeting the primary calendar.
+func applyCalendarIDs(templates []EventTemplate, resolver CalendarResolver) error {
+	names := make([]string, 0, len(templates))
+	seen := map[string]bool{}
+	for _, template := range templates {
+		if template.calendarName != "" && !seen[template.calendarName] {
+			seen[template.calendarName] = true
+			names = append(names, template.calendarName)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	resolvedByName, err := resolver.ResolveCalendarIDs(names)
+	if err != nil {
+		return err
+	}
+	for i := range templates {
+		if templates[i].calendarName != "" {
+			templates[i].calendarID = resolvedByName[templates[i].calendarName]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the externalized program configuration: the time zone all
+// templates are scheduled in, plus the template set itself. It replaces
+// the slice that used to be hardcoded in main().
+type Config struct {
+	TimeZone  string           `yaml:"timeZone" json:"timeZone"`
+	Templates []TemplateConfig `yaml:"templates" json:"templates"`
+}
+
+// TemplateConfig is the serializable form of an EventTemplate. CalendarName
+// is a human-readable calendar name (e.g. "Work", "Health") resolved to a
+// calendar ID at startup via CalendarResolver; it's left empty to target
+// the user's primary calendar.
+type TemplateConfig struct {
+	Summary         string           `yaml:"summary" json:"summary"`
+	Description     string           `yaml:"description" json:"description"`
+	StartTime       string           `yaml:"startTime" json:"startTime"`
+	DurationMinutes int              `yaml:"durationMinutes" json:"durationMinutes"`
+	DaysOfWeek      []string         `yaml:"daysOfWeek" json:"daysOfWeek"`
+	Recurrence      RecurrenceConfig `yaml:"recurrence" json:"recurrence"`
+	ReminderMinutes int64            `yaml:"reminderMinutes" json:"reminderMinutes"`
+	ColorID         string           `yaml:"colorId" json:"colorId"`
+	Kind            string           `yaml:"kind,omitempty" json:"kind,omitempty"`                     // "meal", "workout", or omit
+	ConflictPolicy  string           `yaml:"conflictPolicy,omitempty" json:"conflictPolicy,omitempty"` // "skip", "shift", "overlap"
+	CalendarName    string           `yaml:"calendar,omitempty" json:"calendar,omitempty"`
+}
+
+// RecurrenceConfig is the serializable form of Recurrence.
+type RecurrenceConfig struct {
+	Freq     string   `yaml:"freq" json:"freq"` // DAILY, WEEKLY, MONTHLY, YEARLY
+	Interval int      `yaml:"interval,omitempty" json:"interval,omitempty"`
+	ByDay    []string `yaml:"byDay,omitempty" json:"byDay,omitempty"` // MO, TU, WE, ...
+	Count    int      `yaml:"count,omitempty" json:"count,omitempty"`
+	Until    string   `yaml:"until,omitempty" json:"until,omitempty"` // RFC3339
+	BySetPos []int    `yaml:"bySetPos,omitempty" json:"bySetPos,omitempty"`
+	ExDates  []string `yaml:"exDates,omitempty" json:"exDates,omitempty"` // RFC3339
+}
+
+// LoadConfig reads a YAML or JSON config file, chosen by extension.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to read config file %q (run with --dump-config to generate one): %v", path, err)
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to parse config file %q: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// WriteConfig writes cfg to path as YAML or JSON, chosen by extension.
+func WriteConfig(cfg Config, path string) error {
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(cfg, "", "    ")
+	} else {
+		data, err = yaml.Marshal(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshaling config: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// EventTemplates converts the config's templates into the EventTemplate
+// values the rest of the program works with.
+func (c Config) EventTemplates() ([]EventTemplate, error) {
+	out := make([]EventTemplate, len(c.Templates))
+	for i, tc := range c.Templates {
+		et, err := tc.toEventTemplate()
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %v", tc.Summary, err)
+		}
+		out[i] = et
+	}
+	return out, nil
+}
+
+func (tc TemplateConfig) toEventTemplate() (EventTemplate, error) {
+	days, err := parseWeekdays(tc.DaysOfWeek)
+	if err != nil {
+		return EventTemplate{}, err
+	}
+	rec, err := tc.Recurrence.toRecurrence()
+	if err != nil {
+		return EventTemplate{}, err
+	}
+	if len(days) > 0 && len(rec.ByDay) > 0 && !containsRRuleWeekday(rec.ByDay, toRRuleWeekdays(days[:1])[0]) {
+		return EventTemplate{}, fmt.Errorf("daysOfWeek[0] (%s) is not included in recurrence.byDay %v; the two are specified independently and must agree on the anchor day",
+			tc.DaysOfWeek[0], tc.Recurrence.ByDay)
+	}
+	return EventTemplate{
+		summary:        tc.Summary,
+		description:    tc.Description,
+		startTime:      tc.StartTime,
+		duration:       time.Duration(tc.DurationMinutes) * time.Minute,
+		daysOfWeek:     days,
+		recurrence:     rec,
+		reminderMin:    tc.ReminderMinutes,
+		colorId:        tc.ColorID,
+		kind:           parseKind(tc.Kind),
+		conflictPolicy: ConflictPolicy(tc.ConflictPolicy),
+		calendarName:   tc.CalendarName,
+	}, nil
+}
+
+func (rc RecurrenceConfig) toRecurrence() (Recurrence, error) {
+	freq, err := parseFreq(rc.Freq)
+	if err != nil {
+		return Recurrence{}, err
+	}
+	byDay, err := parseRRuleWeekdays(rc.ByDay)
+	if err != nil {
+		return Recurrence{}, err
+	}
+
+	var until time.Time
+	if rc.Until != "" {
+		until, err = time.Parse(time.RFC3339, rc.Until)
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("error parsing until %q: %v", rc.Until, err)
+		}
+	}
+
+	exDates := make([]time.Time, len(rc.ExDates))
+	for i, raw := range rc.ExDates {
+		exDates[i], err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("error parsing exDate %q: %v", raw, err)
+		}
+	}
+
+	return Recurrence{
+		Freq:     freq,
+		Interval: rc.Interval,
+		ByDay:    byDay,
+		Count:    rc.Count,
+		Until:    until,
+		BySetPos: rc.BySetPos,
+		ExDates:  exDates,
+	}, nil
+}
+
+var weekdaysByName = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday, "friday": time.Friday,
+	"saturday": time.Saturday,
+}
+
+func parseWeekdays(names []string) ([]time.Weekday, error) {
+	out := make([]time.Weekday, len(names))
+	for i, name := range names {
+		day, ok := weekdaysByName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day of week %q", name)
+		}
+		out[i] = day
+	}
+	return out, nil
+}
+
+var rruleWeekdaysByCode = map[string]rrule.Weekday{
+	"SU": rrule.SU, "MO": rrule.MO, "TU": rrule.TU, "WE": rrule.WE,
+	"TH": rrule.TH, "FR": rrule.FR, "SA": rrule.SA,
+}
+
+// containsRRuleWeekday reports whether day appears in byDay.
+func containsRRuleWeekday(byDay []rrule.Weekday, day rrule.Weekday) bool {
+	for _, d := range byDay {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRRuleWeekdays(codes []string) ([]rrule.Weekday, error) {
+	out := make([]rrule.Weekday, len(codes))
+	for i, code := range codes {
+		day, ok := rruleWeekdaysByCode[strings.ToUpper(code)]
+		if !ok {
+			return nil, fmt.Errorf("unknown RRULE weekday %q", code)
+		}
+		out[i] = day
+	}
+	return out, nil
+}
+
+var freqsByName = map[string]rrule.Frequency{
+	"YEARLY": rrule.YEARLY, "MONTHLY": rrule.MONTHLY, "WEEKLY": rrule.WEEKLY,
+	"DAILY": rrule.DAILY, "HOURLY": rrule.HOURLY, "MINUTELY": rrule.MINUTELY,
+	"SECONDLY": rrule.SECONDLY,
+}
+
+func parseFreq(name string) (rrule.Frequency, error) {
+	freq, ok := freqsByName[strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown recurrence frequency %q", name)
+	}
+	return freq, nil
+}
+
+func parseKind(name string) EventKind {
+	switch strings.ToLower(name) {
+	case "meal":
+		return KindMeal
+	case "workout":
+		return KindWorkout
+	default:
+		return KindOther
+	}
+}
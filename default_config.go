@@ -0,0 +1,147 @@
+package main
+
+// DefaultConfig returns this program's original built-in schedule, used
+// to seed a starter config.yaml via --dump-config. Breakfast starts at
+// 08:45 rather than 08:00 so it stays an hour clear of the 07:00 workouts.
+func DefaultConfig() Config {
+	workdays := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+	weekdays := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+	return Config{
+		TimeZone: "Asia/Jakarta",
+		Templates: []TemplateConfig{
+			{
+				Summary:         "Work Hours 💻",
+				Description:     "Remote work time (Berlin office hours)",
+				StartTime:       "10:00",
+				DurationMinutes: 7 * 60,
+				DaysOfWeek:      workdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"MO", "TU", "WE", "TH", "FR"}},
+				ReminderMinutes: 15,
+				ColorID:         "1", // Lavender
+				CalendarName:    "Work",
+			},
+			{
+				Summary:         "Gym or At-Home Workout 🏋️‍♂️ (Check Obsidian)",
+				Description:     "Strength training or cardio session",
+				StartTime:       "7:00",
+				DurationMinutes: 45,
+				DaysOfWeek:      []string{"Monday", "Wednesday", "Friday"},
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"MO", "WE", "FR"}},
+				ReminderMinutes: 15,
+				ColorID:         "10", // Green
+				Kind:            "workout",
+				ConflictPolicy:  "shift",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Light Exercise 🧘‍♂️ (Check Description)",
+				Description:     "Light movement and stretching session",
+				StartTime:       "7:00",
+				DurationMinutes: 30,
+				DaysOfWeek:      []string{"Tuesday", "Thursday"},
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"TU", "TH"}},
+				ReminderMinutes: 15,
+				ColorID:         "7", // Light Green
+				Kind:            "workout",
+				ConflictPolicy:  "shift",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Saturday Grocery Shopping 🛒 (Check Description)",
+				Description:     "Weekly grocery shopping for meal prep",
+				StartTime:       "10:00",
+				DurationMinutes: 2 * 60,
+				DaysOfWeek:      []string{"Saturday"},
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SA"}},
+				ReminderMinutes: 30,
+				ColorID:         "5", // Yellow
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Sunday Meal Prep ⚡️ (Check Description)",
+				Description:     "Weekly meal preparation session",
+				StartTime:       "14:00",
+				DurationMinutes: 3 * 60,
+				DaysOfWeek:      []string{"Sunday"},
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU"}},
+				ReminderMinutes: 30,
+				ColorID:         "5", // Yellow
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Time to Head Home 🏠",
+				Description:     "Evening wrap-up reminder",
+				StartTime:       "22:00",
+				DurationMinutes: 15,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 10,
+				ColorID:         "11", // Red
+			},
+			{
+				Summary:         "Breakfast 🍳",
+				Description:     "GERD-friendly breakfast",
+				StartTime:       "08:45",
+				DurationMinutes: 30,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 10,
+				ColorID:         "5", // Yellow
+				Kind:            "meal",
+				ConflictPolicy:  "skip",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Lunch 🥗",
+				Description:     "GERD-friendly lunch",
+				StartTime:       "12:00",
+				DurationMinutes: 45,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 15,
+				ColorID:         "5", // Yellow
+				Kind:            "meal",
+				ConflictPolicy:  "skip",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Afternoon Snack 🍎",
+				Description:     "GERD-friendly snack",
+				StartTime:       "15:30",
+				DurationMinutes: 15,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 10,
+				ColorID:         "5", // Yellow
+				Kind:            "meal",
+				ConflictPolicy:  "skip",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Dinner 🍲",
+				Description:     "GERD-friendly dinner",
+				StartTime:       "19:00",
+				DurationMinutes: 30,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 15,
+				ColorID:         "5", // Yellow
+				Kind:            "meal",
+				ConflictPolicy:  "skip",
+				CalendarName:    "Health",
+			},
+			{
+				Summary:         "Evening Stretching 🧘‍♂️",
+				Description:     "Evening flexibility routine",
+				StartTime:       "21:30",
+				DurationMinutes: 15,
+				DaysOfWeek:      weekdays,
+				Recurrence:      RecurrenceConfig{Freq: "WEEKLY", ByDay: []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}},
+				ReminderMinutes: 10,
+				ColorID:         "10", // Green
+				CalendarName:    "Health",
+			},
+		},
+	}
+}
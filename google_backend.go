@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	calendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// GoogleBackend implements CalendarBackend against the Google Calendar API.
+type GoogleBackend struct {
+	srv             *calendar.Service
+	calendarsByName map[string]string // cache populated by ResolveCalendarIDs
+}
+
+// NewGoogleBackend reads credentials.json, runs the OAuth flow (prompting
+// the user if no cached token.json exists), and returns a ready-to-use
+// Google Calendar backend.
+func NewGoogleBackend(ctx context.Context) (*GoogleBackend, error) {
+	b, err := os.ReadFile("credentials.json")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client secret file: %v", err)
+	}
+
+	config, err := google.ConfigFromJSON(b, calendar.CalendarScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+	client, err := getClient(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get OAuth client: %v", err)
+	}
+
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
+	}
+
+	return &GoogleBackend{srv: srv}, nil
+}
+
+// CreateRecurringEvent implements CalendarBackend. It creates a single
+// event carrying a canonical RRULE (plus EXDATEs, if any) rather than one
+// event per weekday, so Google's instance deduplication can't fragment
+// the series.
+func (g *GoogleBackend) CreateRecurringEvent(template EventTemplate, timeZone string) (string, error) {
+	finalStartTime, finalEndTime, err := firstOccurrence(template)
+	if err != nil {
+		return "", err
+	}
+
+	rruleLine, err := buildRRule(template.recurrence, finalStartTime)
+	if err != nil {
+		return "", err
+	}
+	recurrence := []string{rruleLine}
+	if exDateLine := buildExDates(template.recurrence.ExDates); exDateLine != "" {
+		recurrence = append(recurrence, exDateLine)
+	}
+
+	event := &calendar.Event{
+		Summary:     template.summary,
+		Description: template.description,
+		Start: &calendar.EventDateTime{
+			DateTime: finalStartTime.Format(time.RFC3339),
+			TimeZone: timeZone,
+		},
+		End: &calendar.EventDateTime{
+			DateTime: finalEndTime.Format(time.RFC3339),
+			TimeZone: timeZone,
+		},
+		Recurrence: recurrence,
+		ColorId:    template.colorId,
+		Reminders: &calendar.EventReminders{
+			Overrides: []*calendar.EventReminder{
+				{
+					Method:  "popup",
+					Minutes: template.reminderMin,
+				},
+			},
+			UseDefault:      false,
+			ForceSendFields: []string{"UseDefault"},
+		},
+	}
+
+	createdEvent, err := g.srv.Events.Insert(targetCalendar(template), event).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to create event: %v", err)
+	}
+	return createdEvent.Id, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// ConflictPolicy controls what happens when a template's intended slot
+// overlaps an existing event on the calendar.
+type ConflictPolicy string
+
+const (
+	ConflictSkip    ConflictPolicy = "skip"    // leave the existing event alone, don't schedule
+	ConflictShift   ConflictPolicy = "shift"   // search forward for the next free slot
+	ConflictOverlap ConflictPolicy = "overlap" // schedule anyway
+)
+
+// EventKind classifies a template for the GERD-aware gap rules below.
+type EventKind int
+
+const (
+	KindOther EventKind = iota
+	KindMeal
+	KindWorkout
+)
+
+// HealthConstraints holds the minimum gaps this program enforces between
+// meals, workouts and sleep, since eating too close to bedtime or
+// exercising too close to a meal aggravates GERD symptoms.
+type HealthConstraints struct {
+	MinGapBetweenMealAndSleep   time.Duration
+	MinGapBetweenWorkoutAndMeal time.Duration
+	Bedtime                     string // "15:04", optional
+	ShiftStep                   time.Duration
+	MaxShift                    time.Duration
+}
+
+// defaultHealthConstraints mirrors the GERD-friendly spacing this
+// calendar's meal and workout templates were designed around.
+var defaultHealthConstraints = HealthConstraints{
+	MinGapBetweenMealAndSleep:   2 * time.Hour,
+	MinGapBetweenWorkoutAndMeal: 1 * time.Hour,
+	Bedtime:                     "23:00",
+	ShiftStep:                   15 * time.Minute,
+	MaxShift:                    3 * time.Hour,
+}
+
+// validateHealthConstraints checks every meal and workout template's
+// time-of-day slot against HealthConstraints before any API calls are
+// made, so a misconfigured template fails fast instead of quietly
+// scheduling an unhealthy slot. Workout/meal pairs are compared only when
+// their daysOfWeek lists share a day; recurrence rules are not expanded.
+func validateHealthConstraints(templates []EventTemplate, constraints HealthConstraints) error {
+	meals := make([]EventTemplate, 0)
+	workouts := make([]EventTemplate, 0)
+	for _, t := range templates {
+		switch t.kind {
+		case KindMeal:
+			meals = append(meals, t)
+		case KindWorkout:
+			workouts = append(workouts, t)
+		}
+	}
+
+	if constraints.Bedtime != "" {
+		bedtime, err := time.Parse("15:04", constraints.Bedtime)
+		if err != nil {
+			return fmt.Errorf("error parsing bedtime: %v", err)
+		}
+		for _, meal := range meals {
+			mealEnd, err := time.Parse("15:04", meal.startTime)
+			if err != nil {
+				return fmt.Errorf("error parsing start time for %q: %v", meal.summary, err)
+			}
+			mealEnd = mealEnd.Add(meal.duration)
+			gap := timeOfDayGap(mealEnd, bedtime)
+			if gap < constraints.MinGapBetweenMealAndSleep {
+				return fmt.Errorf("template %q ends only %v before bedtime, want at least %v",
+					meal.summary, gap, constraints.MinGapBetweenMealAndSleep)
+			}
+		}
+	}
+
+	for _, workout := range workouts {
+		workoutStart, err := time.Parse("15:04", workout.startTime)
+		if err != nil {
+			return fmt.Errorf("error parsing start time for %q: %v", workout.summary, err)
+		}
+		workoutEnd := workoutStart.Add(workout.duration)
+		for _, meal := range meals {
+			mealStart, err := time.Parse("15:04", meal.startTime)
+			if err != nil {
+				return fmt.Errorf("error parsing start time for %q: %v", meal.summary, err)
+			}
+			mealEnd := mealStart.Add(meal.duration)
+			if sharesNoDay(workout.daysOfWeek, meal.daysOfWeek) {
+				continue
+			}
+			// Compare whichever pair of edges is actually adjacent: if the
+			// workout comes first, the gap is from its end to the meal's
+			// start, and vice versa. Overlapping slots give a negative gap.
+			var gap time.Duration
+			if workoutStart.Before(mealStart) {
+				gap = mealStart.Sub(workoutEnd)
+			} else {
+				gap = workoutStart.Sub(mealEnd)
+			}
+			if gap < constraints.MinGapBetweenWorkoutAndMeal {
+				return fmt.Errorf("template %q and %q are only %v apart, want at least %v",
+					workout.summary, meal.summary, gap, constraints.MinGapBetweenWorkoutAndMeal)
+			}
+		}
+	}
+
+	return nil
+}
+
+// timeOfDayGap returns the absolute duration between two times-of-day.
+func timeOfDayGap(a, b time.Time) time.Duration {
+	diff := b.Sub(a)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff
+}
+
+// sharesNoDay reports whether two weekday lists have no day in common.
+func sharesNoDay(a, b []time.Weekday) bool {
+	for _, da := range a {
+		for _, db := range b {
+			if da == db {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ConflictChecker is implemented by backends that can look up existing
+// busy ranges and resolve conflicts before events are inserted.
+type ConflictChecker interface {
+	ResolveConflicts(templates []EventTemplate, timeZone string, weeks int) ([]EventTemplate, error)
+}
+
+// ResolveConflicts implements ConflictChecker for GoogleBackend using a
+// free/busy query over the next `weeks` weeks, checked against each
+// template's first occurrence. Templates with ConflictSkip that land on a
+// busy slot are dropped; ConflictShift templates are moved
+// forward in ShiftStep increments, up to MaxShift, to the first free slot
+// that still satisfies the GERD gap rules; ConflictOverlap templates are
+// passed through unchanged.
+func (g *GoogleBackend) ResolveConflicts(templates []EventTemplate, timeZone string, weeks int) ([]EventTemplate, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone: %v", err)
+	}
+
+	now := time.Now().In(location)
+	timeMax := now.AddDate(0, 0, weeks*7)
+
+	calendarIDs := map[string]bool{}
+	for _, template := range templates {
+		calendarIDs[targetCalendar(template)] = true
+	}
+	items := make([]*calendar.FreeBusyRequestItem, 0, len(calendarIDs))
+	for calID := range calendarIDs {
+		items = append(items, &calendar.FreeBusyRequestItem{Id: calID})
+	}
+
+	fbReq := &calendar.FreeBusyRequest{
+		TimeMin: now.Format(time.RFC3339),
+		TimeMax: timeMax.Format(time.RFC3339),
+		Items:   items,
+	}
+	fbResp, err := g.srv.Freebusy.Query(fbReq).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query free/busy: %v", err)
+	}
+
+	resolved := make([]EventTemplate, 0, len(templates))
+	for _, template := range templates {
+		var busy []*calendar.TimePeriod
+		if cal, ok := fbResp.Calendars[targetCalendar(template)]; ok {
+			busy = cal.Busy
+		}
+
+		start, end, err := firstOccurrence(template)
+		if err != nil {
+			return nil, err
+		}
+
+		if !overlapsAny(start, end, busy) || template.conflictPolicy == ConflictOverlap || template.conflictPolicy == "" {
+			resolved = append(resolved, template)
+			continue
+		}
+
+		if template.conflictPolicy == ConflictSkip {
+			fmt.Printf("Skipping %q: conflicts with an existing event\n", template.summary)
+			continue
+		}
+
+		shifted, ok := shiftTemplate(template, start, end, busy, defaultHealthConstraints, templates)
+		if !ok {
+			fmt.Printf("Could not find a free slot for %q within %v, leaving as scheduled\n",
+				template.summary, defaultHealthConstraints.MaxShift)
+			resolved = append(resolved, template)
+			continue
+		}
+		resolved = append(resolved, shifted)
+	}
+
+	return resolved, nil
+}
+
+// shiftTemplate searches forward in constraints.ShiftStep increments for a
+// start time that is both free and still honours the GERD gap rules,
+// returning a copy of template with startTime updated.
+func shiftTemplate(template EventTemplate, start, end time.Time, busy []*calendar.TimePeriod, constraints HealthConstraints, allTemplates []EventTemplate) (EventTemplate, bool) {
+	for offset := constraints.ShiftStep; offset <= constraints.MaxShift; offset += constraints.ShiftStep {
+		candidateStart := start.Add(offset)
+		candidateEnd := end.Add(offset)
+		if overlapsAny(candidateStart, candidateEnd, busy) {
+			continue
+		}
+
+		candidate := template
+		candidate.startTime = candidateStart.Format("15:04")
+		if err := validateHealthConstraints(withReplaced(allTemplates, template.summary, candidate), constraints); err != nil {
+			continue
+		}
+		return candidate, true
+	}
+	return EventTemplate{}, false
+}
+
+// withReplaced returns a copy of templates with the entry matching
+// summary swapped for replacement, used to re-validate gap rules against
+// a shifted candidate.
+func withReplaced(templates []EventTemplate, summary string, replacement EventTemplate) []EventTemplate {
+	out := make([]EventTemplate, len(templates))
+	for i, t := range templates {
+		if t.summary == summary {
+			out[i] = replacement
+		} else {
+			out[i] = t
+		}
+	}
+	return out
+}
+
+// overlapsAny reports whether [start, end) intersects any busy period.
+func overlapsAny(start, end time.Time, busy []*calendar.TimePeriod) bool {
+	for _, period := range busy {
+		busyStart, err := time.Parse(time.RFC3339, period.Start)
+		if err != nil {
+			continue
+		}
+		busyEnd, err := time.Parse(time.RFC3339, period.End)
+		if err != nil {
+			continue
+		}
+		if start.Before(busyEnd) && busyStart.Before(end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Recurrence describes an RFC 5545 recurrence rule for an EventTemplate.
+// It replaces the old one-event-per-weekday hack with a single canonical
+// RRULE, letting templates express daily/monthly/"last Friday of month"
+// schedules without any code changes.
+type Recurrence struct {
+	Freq     rrule.Frequency
+	Interval int
+	ByDay    []rrule.Weekday
+	Count    int
+	Until    time.Time
+	BySetPos []int
+	ExDates  []time.Time
+}
+
+// firstOccurrence anchors a template's recurrence to its first occurrence
+// next week (the week starting today, when today is a Monday), which is
+// where backends start the RRULE from.
+func firstOccurrence(template EventTemplate) (start, end time.Time, err error) {
+	now := time.Now()
+	daysUntilMonday := (8 - int(now.Weekday())) % 7
+	startDate := now.AddDate(0, 0, daysUntilMonday)
+
+	firstDay, err := anchorWeekday(template, startDate.Weekday())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	dayOffset := (int(firstDay) - int(startDate.Weekday()) + 7) % 7
+	eventStart := startDate.AddDate(0, 0, dayOffset)
+
+	eventStartTime, err := time.Parse("15:04", template.startTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error parsing time: %v", err)
+	}
+
+	start = time.Date(
+		eventStart.Year(), eventStart.Month(), eventStart.Day(),
+		eventStartTime.Hour(), eventStartTime.Minute(), 0, 0,
+		eventStart.Location(),
+	)
+	end = start.Add(template.duration)
+	return start, end, nil
+}
+
+// anchorWeekday returns the weekday a template's first occurrence should
+// land on. It prefers daysOfWeek[0], and falls back to the recurrence's
+// ByDay for templates (e.g. a plain DAILY schedule) that only set
+// Recurrence; if neither says anything about weekdays, any day fires the
+// same schedule, so it defaults to fallback instead of failing.
+func anchorWeekday(template EventTemplate, fallback time.Weekday) (time.Weekday, error) {
+	if len(template.daysOfWeek) > 0 {
+		return template.daysOfWeek[0], nil
+	}
+	if len(template.recurrence.ByDay) > 0 {
+		return fromRRuleWeekday(template.recurrence.ByDay[0])
+	}
+	return fallback, nil
+}
+
+// fromRRuleWeekday converts an rrule-go Weekday back to time.Weekday.
+func fromRRuleWeekday(day rrule.Weekday) (time.Weekday, error) {
+	table := map[rrule.Weekday]time.Weekday{
+		rrule.SU: time.Sunday, rrule.MO: time.Monday, rrule.TU: time.Tuesday,
+		rrule.WE: time.Wednesday, rrule.TH: time.Thursday, rrule.FR: time.Friday, rrule.SA: time.Saturday,
+	}
+	weekday, ok := table[day]
+	if !ok {
+		return 0, fmt.Errorf("unsupported RRULE weekday %v", day)
+	}
+	return weekday, nil
+}
+
+// toRRuleWeekdays converts time.Weekday values to rrule-go's Weekday type.
+func toRRuleWeekdays(days []time.Weekday) []rrule.Weekday {
+	table := map[time.Weekday]rrule.Weekday{
+		time.Sunday:    rrule.SU,
+		time.Monday:    rrule.MO,
+		time.Tuesday:   rrule.TU,
+		time.Wednesday: rrule.WE,
+		time.Thursday:  rrule.TH,
+		time.Friday:    rrule.FR,
+		time.Saturday:  rrule.SA,
+	}
+	out := make([]rrule.Weekday, len(days))
+	for i, d := range days {
+		out[i] = table[d]
+	}
+	return out
+}
+
+// buildRRule turns a Recurrence into the "RRULE:..." line Google Calendar
+// and CalDAV both expect, anchored at dtstart.
+func buildRRule(rec Recurrence, dtstart time.Time) (string, error) {
+	opts := rrule.ROption{
+		Freq:     rec.Freq,
+		Interval: rec.Interval,
+		Dtstart:  dtstart,
+	}
+	if rec.Interval == 0 {
+		opts.Interval = 1
+	}
+	if len(rec.ByDay) > 0 {
+		opts.Byweekday = rec.ByDay
+	}
+	if rec.Count > 0 {
+		opts.Count = rec.Count
+	}
+	if !rec.Until.IsZero() {
+		opts.Until = rec.Until
+	}
+	if len(rec.BySetPos) > 0 {
+		opts.Bysetpos = rec.BySetPos
+	}
+
+	rule, err := rrule.NewRRule(opts)
+	if err != nil {
+		return "", fmt.Errorf("error building RRULE: %v", err)
+	}
+
+	for _, line := range strings.Split(rule.String(), "\n") {
+		if strings.HasPrefix(line, "RRULE:") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("rrule-go produced no RRULE line")
+}
+
+// buildExDates renders Recurrence.ExDates as a single "EXDATE:..." line,
+// or "" if there are none.
+func buildExDates(exDates []time.Time) string {
+	if len(exDates) == 0 {
+		return ""
+	}
+	formatted := make([]string, len(exDates))
+	for i, d := range exDates {
+		formatted[i] = d.UTC().Format("20060102T150405Z")
+	}
+	return "EXDATE:" + strings.Join(formatted, ",")
+}
+
+// validateRecurrence checks that a template's recurrence actually produces
+// at least one occurrence in the next 30 days, so a typo'd Until/Count/ByDay
+// fails fast at load time instead of silently scheduling nothing.
+func validateRecurrence(rec Recurrence, dtstart time.Time) error {
+	opts := rrule.ROption{
+		Freq:     rec.Freq,
+		Interval: rec.Interval,
+		Dtstart:  dtstart,
+	}
+	if opts.Interval == 0 {
+		opts.Interval = 1
+	}
+	if len(rec.ByDay) > 0 {
+		opts.Byweekday = rec.ByDay
+	}
+	if rec.Count > 0 {
+		opts.Count = rec.Count
+	}
+	if !rec.Until.IsZero() {
+		opts.Until = rec.Until
+	}
+	if len(rec.BySetPos) > 0 {
+		opts.Bysetpos = rec.BySetPos
+	}
+
+	rule, err := rrule.NewRRule(opts)
+	if err != nil {
+		return fmt.Errorf("error building RRULE: %v", err)
+	}
+
+	now := time.Now()
+	occurrences := rule.Between(now, now.AddDate(0, 0, 30), true)
+	if len(occurrences) == 0 {
+		return fmt.Errorf("recurrence produces no occurrence in the next 30 days")
+	}
+	return nil
+}
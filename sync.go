@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// defaultSyncWeeks is how far ahead sync mode looks when comparing
+// existing events against templates.
+const defaultSyncWeeks = 4
+
+// Syncer is implemented by backends that can make repeated runs
+// idempotent by comparing already-scheduled events against the template
+// set instead of blindly inserting duplicates.
+type Syncer interface {
+	Sync(templates []EventTemplate, timeZone string, weeks int) error
+}
+
+// Sync implements Syncer for GoogleBackend using the standard "list events
+// in a range, diff against templates" pattern: matching events are left
+// alone, drifted events are patched in place, and orphaned IDs from a
+// previous run that no longer match any template are deleted if the event
+// still has an instance inside the listed window.
+func (g *GoogleBackend) Sync(templates []EventTemplate, timeZone string, weeks int) error {
+	now := time.Now()
+	timeMin := now.Format(time.RFC3339)
+	timeMax := now.AddDate(0, 0, weeks*7).Format(time.RFC3339)
+
+	calendarIDs := map[string]bool{}
+	for _, template := range templates {
+		calendarIDs[targetCalendar(template)] = true
+	}
+
+	type located struct {
+		calendarID string
+		event      *calendar.Event
+	}
+	byID := make(map[string]located)
+	bySummary := make(map[string]located)
+	for calID := range calendarIDs {
+		existing, err := g.srv.Events.List(calID).
+			TimeMin(timeMin).
+			TimeMax(timeMax).
+			SingleEvents(true).
+			OrderBy("startTime").
+			Do()
+		if err != nil {
+			return fmt.Errorf("unable to list existing events on %q: %v", calID, err)
+		}
+		for _, item := range existing.Items {
+			loc := located{calendarID: calID, event: item}
+			byID[masterEventID(item)] = loc
+			if _, ok := bySummary[item.Summary]; !ok {
+				bySummary[item.Summary] = loc
+			}
+		}
+	}
+
+	storedIds, err := loadEventIds()
+	if err != nil {
+		return fmt.Errorf("unable to load stored event IDs: %v", err)
+	}
+
+	eventIds := make(map[string]string, len(templates))
+	for _, template := range templates {
+		match, found := bySummary[template.summary]
+		if !found {
+			fmt.Printf("Creating new event: %s\n", template.summary)
+			eventId, err := g.CreateRecurringEvent(template, timeZone)
+			if err != nil {
+				fmt.Printf("Error creating event '%s': %v\n", template.summary, err)
+				continue
+			}
+			eventIds[template.summary] = eventId
+			continue
+		}
+
+		finalStartTime, finalEndTime, err := firstOccurrence(template)
+		if err != nil {
+			return err
+		}
+
+		masterID := masterEventID(match.event)
+		if eventDrifted(match.event, finalStartTime, finalEndTime) {
+			fmt.Printf("Updating drifted event: %s\n", template.summary)
+			// Patch the master recurring event rather than the matched
+			// instance, so the whole series moves instead of detaching a
+			// single occurrence.
+			master, err := g.srv.Events.Get(match.calendarID, masterID).Do()
+			if err != nil {
+				return fmt.Errorf("unable to fetch master event for '%s': %v", template.summary, err)
+			}
+			master.Start = &calendar.EventDateTime{DateTime: finalStartTime.Format(time.RFC3339), TimeZone: timeZone}
+			master.End = &calendar.EventDateTime{DateTime: finalEndTime.Format(time.RFC3339), TimeZone: timeZone}
+			if _, err := g.srv.Events.Update(match.calendarID, masterID, master).Do(); err != nil {
+				return fmt.Errorf("unable to update event '%s': %v", template.summary, err)
+			}
+		} else {
+			fmt.Printf("Up to date: %s\n", template.summary)
+		}
+		eventIds[template.summary] = masterID
+	}
+
+	for summary, id := range storedIds {
+		if _, stillWanted := eventIds[summary]; stillWanted {
+			continue
+		}
+		loc, stillOnCalendar := byID[id]
+		if !stillOnCalendar {
+			continue
+		}
+		fmt.Printf("Deleting orphaned event: %s\n", summary)
+		if err := g.srv.Events.Delete(loc.calendarID, id).Do(); err != nil {
+			return fmt.Errorf("unable to delete orphaned event '%s': %v", summary, err)
+		}
+	}
+
+	return saveEventIds(eventIds)
+}
+
+// masterEventID returns the ID of a recurring event's master series,
+// since SingleEvents(true) returns per-instance events whose own Id is a
+// composite instance ID; the master ID only appears in
+// RecurringEventId. Non-recurring events have no RecurringEventId, so
+// their own Id is already the master (and only) ID.
+func masterEventID(event *calendar.Event) string {
+	if event.RecurringEventId != "" {
+		return event.RecurringEventId
+	}
+	return event.Id
+}
+
+// eventDrifted reports whether an existing event's time of day or duration
+// no longer matches the template's intended slot. The matched instance
+// usually falls on another date than the template's anchored first
+// occurrence, so dates are not compared.
+func eventDrifted(event *calendar.Event, wantStart, wantEnd time.Time) bool {
+	if event.Start == nil || event.End == nil {
+		return true
+	}
+	gotStart, err := time.Parse(time.RFC3339, event.Start.DateTime)
+	if err != nil {
+		return true
+	}
+	gotEnd, err := time.Parse(time.RFC3339, event.End.DateTime)
+	if err != nil {
+		return true
+	}
+	gotStart = gotStart.In(wantStart.Location())
+	gotHour, gotMin, _ := gotStart.Clock()
+	wantHour, wantMin, _ := wantStart.Clock()
+	return gotHour != wantHour || gotMin != wantMin || gotEnd.Sub(gotStart) != wantEnd.Sub(wantStart)
+}